@@ -0,0 +1,94 @@
+// Command newrelic-pixie-integration runs the Pixie->OTLP worker pool and
+// its self-observability /metrics endpoint.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/newrelic/infrastructure-agent/pkg/log"
+	"github.com/newrelic/newrelic-pixie-integration/internal/adapter"
+	"github.com/newrelic/newrelic-pixie-integration/internal/config"
+	"github.com/newrelic/newrelic-pixie-integration/internal/exporter"
+	"github.com/newrelic/newrelic-pixie-integration/internal/metrics"
+	"github.com/newrelic/newrelic-pixie-integration/internal/worker"
+	"px.dev/pxapi"
+)
+
+func main() {
+	cfg, err := config.NewWorkerFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	exp, err := exporter.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rh := adapter.NewResourceHelper(cfg.ResourceCacheMaxEntries(), cfg.ResourceCacheTTL())
+
+	metricsSrv := metrics.Serve(cfg.MetricsAddr())
+	go metrics.WatchExporter(ctx, exp, 15*time.Second)
+	go metrics.WatchResourceCache(ctx, rh, 15*time.Second)
+
+	vz, err := pxapi.NewVizierClient(ctx, cfg.PixieClusterID())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := worker.Build(ctx, cfg, vz, exp, rh)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go w.Metrics(adapter.NewJVM(cfg.ClusterName(), cfg.PixieClusterID(), 10, cfg.AdapterFilter("jvm")), &wg)
+
+	if dir := cfg.ScriptManifestDir(); dir != "" {
+		manifests, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+		if err != nil {
+			log.Warnf("failed to list script manifests in %s: %s", dir, err)
+		}
+		for _, manifestPath := range manifests {
+			provider, err := adapter.NewFileScriptProvider(manifestPath)
+			if err != nil {
+				log.Warnf("failed to load script manifest %s: %s", manifestPath, err)
+				continue
+			}
+			id := provider.Manifest().ID
+			switch signal := provider.Manifest().Signal; signal {
+			case adapter.SignalMetrics, "":
+				wg.Add(1)
+				go w.Metrics(adapter.NewGenericMetrics(cfg.ClusterName(), cfg.PixieClusterID(), provider, cfg.AdapterFilter(id)), &wg)
+			default:
+				// SignalSpans and any other declared signal: there is no
+				// generic SpansAdapter yet, so refuse to silently run a
+				// spans-producing script through the metrics pipeline.
+				log.Warnf("script manifest %s (%s) declares signal %q, which isn't supported yet; skipping", manifestPath, id, signal)
+				provider.Close()
+			}
+		}
+	}
+
+	wg.Wait()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	exp.Shutdown(shutdownCtx)
+
+	metricsShutdownCtx, metricsShutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer metricsShutdownCancel()
+	metricsSrv.Shutdown(metricsShutdownCtx)
+}
@@ -0,0 +1,51 @@
+// Package adapter converts the records produced by a Pixie script into OTLP
+// resource metrics or resource spans.
+package adapter
+
+import (
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"github.com/newrelic/newrelic-pixie-integration/internal/config"
+	"px.dev/pxapi/types"
+)
+
+var instrumentationLibrary = &commonpb.InstrumentationLibrary{
+	Name: "newrelic-pixie-integration",
+}
+
+// MetricsAdapter turns the output rows of a metrics-producing Pixie script
+// into OTLP resource metrics.
+type MetricsAdapter interface {
+	ID() string
+	CollectIntervalSec() int64
+	Script() string
+	Adapt(rh *ResourceHelper, r *types.Record) ([]*metricpb.ResourceMetrics, error)
+}
+
+// SpansAdapter turns the output rows of a spans-producing Pixie script into
+// OTLP resource spans.
+type SpansAdapter interface {
+	ID() string
+	CollectIntervalSec() int64
+	Script() string
+	Adapt(rh *ResourceHelper, r *types.Record) ([]*tracepb.ResourceSpans, error)
+}
+
+// NewJVM builds the MetricsAdapter for the jvm_stats Pixie script.
+func NewJVM(clusterName, pixieClusterID string, collectIntervalSec int64, filterCfg config.AdapterFilter) MetricsAdapter {
+	return newJvm(clusterName, pixieClusterID, collectIntervalSec, filterCfg)
+}
+
+func createArrayOfMetrics(resources []*resourcepb.Resource, ilm []*metricpb.InstrumentationLibraryMetrics) []*metricpb.ResourceMetrics {
+	rms := make([]*metricpb.ResourceMetrics, len(resources))
+	for i, res := range resources {
+		rms[i] = &metricpb.ResourceMetrics{
+			Resource:                      res,
+			InstrumentationLibraryMetrics: ilm,
+		}
+	}
+	return rms
+}
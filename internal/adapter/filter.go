@@ -0,0 +1,51 @@
+package adapter
+
+import (
+	"path"
+
+	"github.com/newrelic/newrelic-pixie-integration/internal/config"
+)
+
+// metricFilter implements the include/exclude-glob and attribute-drop rules
+// from a config.AdapterFilter. It is shared by every MetricsAdapter so a new
+// adapter only has to run its metric names and attribute keys through it.
+type metricFilter struct {
+	include   []string
+	exclude   []string
+	dropAttrs map[string]bool
+}
+
+func newMetricFilter(cfg config.AdapterFilter) *metricFilter {
+	drop := make(map[string]bool, len(cfg.AttributeDrop))
+	for _, a := range cfg.AttributeDrop {
+		drop[a] = true
+	}
+	return &metricFilter{
+		include:   cfg.IncludeMetrics,
+		exclude:   cfg.ExcludeMetrics,
+		dropAttrs: drop,
+	}
+}
+
+// allowMetric reports whether a metric should be emitted: it must match at
+// least one include glob (when any are configured) and must not match any
+// exclude glob.
+func (f *metricFilter) allowMetric(name string) bool {
+	if len(f.include) > 0 && !matchesAny(f.include, name) {
+		return false
+	}
+	return !matchesAny(f.exclude, name)
+}
+
+func (f *metricFilter) dropAttribute(key string) bool {
+	return f.dropAttrs[key]
+}
+
+func matchesAny(globs []string, name string) bool {
+	for _, g := range globs {
+		if ok, err := path.Match(g, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/newrelic/newrelic-pixie-integration/internal/config"
+)
+
+func TestMatchesAny(t *testing.T) {
+	tests := []struct {
+		name  string
+		globs []string
+		s     string
+		want  bool
+	}{
+		{"empty globs", nil, "runtime.jvm.gc.collection", false},
+		{"exact match", []string{"runtime.jvm.gc.collection"}, "runtime.jvm.gc.collection", true},
+		{"no match", []string{"runtime.jvm.gc.collection"}, "runtime.jvm.memory.area", false},
+		{"wildcard suffix", []string{"runtime.jvm.*"}, "runtime.jvm.memory.area", true},
+		{"wildcard no match on different prefix", []string{"runtime.jvm.*"}, "runtime.other.memory.area", false},
+		{"second glob matches", []string{"runtime.jvm.gc.*", "runtime.jvm.memory.*"}, "runtime.jvm.memory.area", true},
+		{"invalid pattern is ignored, not fatal", []string{"["}, "anything", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesAny(tt.globs, tt.s); got != tt.want {
+				t.Errorf("matchesAny(%v, %q) = %v, want %v", tt.globs, tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricFilterAllowMetric(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.AdapterFilter
+		want map[string]bool
+	}{
+		{
+			name: "no filters allows everything",
+			cfg:  config.AdapterFilter{},
+			want: map[string]bool{
+				"runtime.jvm.gc.collection": true,
+				"runtime.jvm.memory.area":   true,
+			},
+		},
+		{
+			name: "include restricts to matching globs",
+			cfg:  config.AdapterFilter{IncludeMetrics: []string{"runtime.jvm.gc.*"}},
+			want: map[string]bool{
+				"runtime.jvm.gc.collection": true,
+				"runtime.jvm.memory.area":   false,
+			},
+		},
+		{
+			name: "exclude removes matching globs",
+			cfg:  config.AdapterFilter{ExcludeMetrics: []string{"runtime.jvm.memory.*"}},
+			want: map[string]bool{
+				"runtime.jvm.gc.collection": true,
+				"runtime.jvm.memory.area":   false,
+			},
+		},
+		{
+			name: "exclude wins when a metric matches both include and exclude",
+			cfg: config.AdapterFilter{
+				IncludeMetrics: []string{"runtime.jvm.*"},
+				ExcludeMetrics: []string{"runtime.jvm.memory.*"},
+			},
+			want: map[string]bool{
+				"runtime.jvm.gc.collection": true,
+				"runtime.jvm.memory.area":   false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newMetricFilter(tt.cfg)
+			for metric, want := range tt.want {
+				if got := f.allowMetric(metric); got != want {
+					t.Errorf("allowMetric(%q) = %v, want %v", metric, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMetricFilterDropAttribute(t *testing.T) {
+	f := newMetricFilter(config.AdapterFilter{AttributeDrop: []string{"pod"}})
+	if !f.dropAttribute("pod") {
+		t.Error("dropAttribute(\"pod\") = false, want true")
+	}
+	if f.dropAttribute("container") {
+		t.Error("dropAttribute(\"container\") = true, want false")
+	}
+}
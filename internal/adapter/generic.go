@@ -0,0 +1,103 @@
+package adapter
+
+import (
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+
+	"github.com/newrelic/newrelic-pixie-integration/internal/config"
+	"px.dev/pxapi/types"
+)
+
+// genericMetrics is the MetricsAdapter for any manifest-defined script: it
+// replaces the need to write a Go type like jvm.go for every new workload.
+// Its script and metric mapping come from a ScriptProvider and are
+// hot-swapped in place when the provider reports a change.
+type genericMetrics struct {
+	provider       ScriptProvider
+	clusterName    string
+	pixieClusterID string
+	filter         *metricFilter
+	changed        chan struct{}
+}
+
+// NewGenericMetrics builds a MetricsAdapter whose script and metric mapping
+// are sourced from provider instead of compiled into the binary.
+func NewGenericMetrics(clusterName, pixieClusterID string, provider ScriptProvider, filterCfg config.AdapterFilter) MetricsAdapter {
+	a := &genericMetrics{
+		provider:       provider,
+		clusterName:    clusterName,
+		pixieClusterID: pixieClusterID,
+		filter:         newMetricFilter(filterCfg),
+		changed:        make(chan struct{}, 1),
+	}
+	go a.relayChanges()
+	return a
+}
+
+// relayChanges forwards the provider's change notifications to the
+// adapter's own Changed() channel, so callers only depend on the adapter
+// interface rather than reaching into the provider.
+func (a *genericMetrics) relayChanges() {
+	for range a.provider.Changed() {
+		select {
+		case a.changed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (a *genericMetrics) ID() string {
+	return a.provider.Manifest().ID
+}
+
+func (a *genericMetrics) CollectIntervalSec() int64 {
+	return a.provider.Manifest().CollectIntervalSec
+}
+
+func (a *genericMetrics) Script() string {
+	return a.provider.Script()
+}
+
+// Changed implements the worker package's optional hot-reload interface.
+func (a *genericMetrics) Changed() <-chan struct{} {
+	return a.changed
+}
+
+func (a *genericMetrics) Adapt(rh *ResourceHelper, r *types.Record) ([]*metricpb.ResourceMetrics, error) {
+	timestamp := r.GetDatum("time_").(*types.Time64NSValue).Value()
+	timestampUnixNano := uint64(timestamp.UnixNano())
+	resourceID := k8sResourceID(r)
+	mapping := a.provider.Manifest().MetricMapping
+
+	instrumentationLibraries := make([]*metricpb.InstrumentationLibraryMetrics, 0, len(mapping))
+	resources := rh.createResources(r, a.clusterName, a.pixieClusterID)
+	for column, manifestDef := range mapping {
+		def := metricDef{
+			metricName:  manifestDef.MetricName,
+			description: manifestDef.Description,
+			unit:        manifestDef.Unit,
+			attributes:  manifestDef.Attributes,
+			temporality: parseTemporality(manifestDef.Temporality),
+		}
+		if !a.filter.allowMetric(def.metricName) {
+			continue
+		}
+		value, err := getFloatDatum(r, column)
+		if err != nil {
+			return nil, err
+		}
+		instrumentationLibraries = append(instrumentationLibraries, &metricpb.InstrumentationLibraryMetrics{
+			InstrumentationLibrary: instrumentationLibrary,
+			Metrics: []*metricpb.Metric{
+				buildMetric(rh, def, column, resourceID, timestampUnixNano, value, a.filter),
+			},
+		})
+	}
+	return createArrayOfMetrics(resources, instrumentationLibraries), nil
+}
+
+func parseTemporality(s string) temporality {
+	if s == "delta" {
+		return temporalityDelta
+	}
+	return temporalityGauge
+}
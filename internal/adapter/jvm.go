@@ -8,6 +8,7 @@ import (
 	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
 	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
 
+	"github.com/newrelic/newrelic-pixie-integration/internal/config"
 	"px.dev/pxapi/types"
 )
 
@@ -59,18 +60,30 @@ px.display(by_k8s, 'jvm')
 `
 
 var metricMapping = map[string]metricDef{
-	"young_gc_time":   {"runtime.jvm.gc.collection", "", "ms", map[string]interface{}{"gc": "young"}},
-	"full_gc_time":    {"runtime.jvm.gc.collection", "", "ms", map[string]interface{}{"gc": "full"}},
-	"used_heap_size":  {"runtime.jvm.memory.area", "", "bytes", map[string]interface{}{"type": "used", "area": "heap"}},
-	"total_heap_size": {"runtime.jvm.memory.area", "", "bytes", map[string]interface{}{"type": "total", "area": "heap"}},
-	"max_heap_size":   {"runtime.jvm.memory.area", "", "bytes", map[string]interface{}{"type": "max", "area": "heap"}},
+	"young_gc_time":   {"runtime.jvm.gc.collection", "", "ms", map[string]interface{}{"gc": "young"}, temporalityDelta},
+	"full_gc_time":    {"runtime.jvm.gc.collection", "", "ms", map[string]interface{}{"gc": "full"}, temporalityDelta},
+	"used_heap_size":  {"runtime.jvm.memory.area", "", "bytes", map[string]interface{}{"type": "used", "area": "heap"}, temporalityGauge},
+	"total_heap_size": {"runtime.jvm.memory.area", "", "bytes", map[string]interface{}{"type": "total", "area": "heap"}, temporalityGauge},
+	"max_heap_size":   {"runtime.jvm.memory.area", "", "bytes", map[string]interface{}{"type": "max", "area": "heap"}, temporalityGauge},
 }
 
+// temporality selects how a metricDef's samples are reported in OTLP. The
+// JVM GC metrics arrive from Pixie as already-windowed counter deltas
+// (max-min over the window), so they are reported as monotonic Sum metrics
+// with DELTA temporality rather than Gauges.
+type temporality int
+
+const (
+	temporalityGauge temporality = iota
+	temporalityDelta
+)
+
 type metricDef struct {
 	metricName  string
 	description string
 	unit        string
 	attributes  map[string]interface{}
+	temporality temporality
 }
 
 type jvm struct {
@@ -78,10 +91,17 @@ type jvm struct {
 	pixieClusterID     string
 	collectIntervalSec int64
 	script             string
+	filter             *metricFilter
 }
 
-func newJvm(clusterName, pixieClusterID string, collectIntervalSec int64) *jvm {
-	return &jvm{clusterName, pixieClusterID, collectIntervalSec, fmt.Sprintf(jvmTemplate, collectIntervalSec)}
+func newJvm(clusterName, pixieClusterID string, collectIntervalSec int64, filterCfg config.AdapterFilter) *jvm {
+	return &jvm{
+		clusterName:        clusterName,
+		pixieClusterID:     pixieClusterID,
+		collectIntervalSec: collectIntervalSec,
+		script:             fmt.Sprintf(jvmTemplate, collectIntervalSec),
+		filter:             newMetricFilter(filterCfg),
+	}
 }
 
 func (a *jvm) ID() string {
@@ -98,41 +118,68 @@ func (a *jvm) Script() string {
 
 func (a *jvm) Adapt(rh *ResourceHelper, r *types.Record) ([]*metricpb.ResourceMetrics, error) {
 	timestamp := r.GetDatum("time_").(*types.Time64NSValue).Value()
-	instrumentationLibraries := make([]*metricpb.InstrumentationLibraryMetrics, len(metricMapping))
+	timestampUnixNano := uint64(timestamp.UnixNano())
+	resourceID := k8sResourceID(r)
+	instrumentationLibraries := make([]*metricpb.InstrumentationLibraryMetrics, 0, len(metricMapping))
 	resources := rh.createResources(r, a.clusterName, a.pixieClusterID)
-	index := 0
 	for metricName, def := range metricMapping {
-		value, err := getValueFromJVMMetric(r, metricName)
+		if !a.filter.allowMetric(def.metricName) {
+			continue
+		}
+		value, err := getFloatDatum(r, metricName)
 		if err != nil {
 			return nil, err
 		}
-		instrumentationLibraries[index] = &metricpb.InstrumentationLibraryMetrics{
+		instrumentationLibraries = append(instrumentationLibraries, &metricpb.InstrumentationLibraryMetrics{
 			InstrumentationLibrary: instrumentationLibrary,
 			Metrics: []*metricpb.Metric{
-				{
-					Name:        def.metricName,
-					Description: def.description,
-					Unit:        def.unit,
-					Data: &metricpb.Metric_Gauge{
-						Gauge: &metricpb.Gauge{
-							DataPoints: []*metricpb.NumberDataPoint{
-								{
-									TimeUnixNano: uint64(timestamp.UnixNano()),
-									Value:        &metricpb.NumberDataPoint_AsDouble{value},
-									Labels:       transformAttributes(def.attributes),
-								},
-							},
-						},
-					},
-				},
+				buildMetric(rh, def, metricName, resourceID, timestampUnixNano, value, a.filter),
 			},
-		}
-		index++
+		})
 	}
 	return createArrayOfMetrics(resources, instrumentationLibraries), nil
 }
 
-func getValueFromJVMMetric(r *types.Record, metricName string) (float64, error) {
+// k8sResourceID identifies the k8s object a by_k8s-style row was aggregated
+// over, which is the unit ResourceHelper's start-time cache tracks (see the
+// seriesKey doc comment).
+func k8sResourceID(r *types.Record) string {
+	return fmt.Sprintf("%v/%v/%v/%v",
+		r.GetDatum("namespace"), r.GetDatum("service"), r.GetDatum("pod"), r.GetDatum("container"))
+}
+
+func buildMetric(rh *ResourceHelper, def metricDef, metricName, resourceID string, timestampUnixNano uint64, value float64, filter *metricFilter) *metricpb.Metric {
+	m := &metricpb.Metric{
+		Name:        def.metricName,
+		Description: def.description,
+		Unit:        def.unit,
+	}
+	dataPoint := &metricpb.NumberDataPoint{
+		TimeUnixNano: timestampUnixNano,
+		Value:        &metricpb.NumberDataPoint_AsDouble{value},
+		Labels:       transformAttributes(def.attributes, filter),
+	}
+	switch def.temporality {
+	case temporalityDelta:
+		dataPoint.StartTimeUnixNano = rh.StartTime(resourceID, metricName, timestampUnixNano)
+		m.Data = &metricpb.Metric_Sum{
+			Sum: &metricpb.Sum{
+				DataPoints:             []*metricpb.NumberDataPoint{dataPoint},
+				AggregationTemporality: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+				IsMonotonic:            true,
+			},
+		}
+	default:
+		m.Data = &metricpb.Metric_Gauge{
+			Gauge: &metricpb.Gauge{
+				DataPoints: []*metricpb.NumberDataPoint{dataPoint},
+			},
+		}
+	}
+	return m
+}
+
+func getFloatDatum(r *types.Record, metricName string) (float64, error) {
 	valueDatum := r.GetDatum(metricName)
 	var value float64
 	if valueDatum.Type() == vizierpb.INT64 {
@@ -145,9 +192,12 @@ func getValueFromJVMMetric(r *types.Record, metricName string) (float64, error)
 	return value, nil
 }
 
-func transformAttributes(attrs map[string]interface{}) []*commonpb.StringKeyValue {
+func transformAttributes(attrs map[string]interface{}, filter *metricFilter) []*commonpb.StringKeyValue {
 	stringKeyValues := make([]*commonpb.StringKeyValue, 0)
 	for k := range attrs {
+		if filter.dropAttribute(k) {
+			continue
+		}
 		stringKeyValues = append(stringKeyValues, &commonpb.StringKeyValue{
 			Key:   k,
 			Value: fmt.Sprintf("%v", attrs[k]),
@@ -0,0 +1,172 @@
+package adapter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+
+	"github.com/newrelic/infrastructure-agent/pkg/log"
+)
+
+// Signal is the kind of OTLP data a manifest-defined script produces.
+type Signal string
+
+const (
+	SignalMetrics Signal = "metrics"
+	SignalSpans   Signal = "spans"
+)
+
+// Manifest is the YAML description of a dynamically-loaded PxL script: it
+// replaces a hardcoded Go adapter (like jvm.go) with data a new workload can
+// ship without a recompile.
+type Manifest struct {
+	ID                 string                       `yaml:"id"`
+	CollectIntervalSec int64                        `yaml:"collect_interval_sec"`
+	ScriptPath         string                       `yaml:"script_path"`
+	Signal             Signal                       `yaml:"signal"`
+	MetricMapping      map[string]ManifestMetricDef `yaml:"metric_mapping"`
+}
+
+// ManifestMetricDef is the YAML equivalent of metricDef: the mapping from a
+// Pixie output column to an OTLP metric.
+type ManifestMetricDef struct {
+	MetricName  string                 `yaml:"metric_name"`
+	Description string                 `yaml:"description"`
+	Unit        string                 `yaml:"unit"`
+	Attributes  map[string]interface{} `yaml:"attributes"`
+	Temporality string                 `yaml:"temporality"` // "gauge" (default) or "delta"
+}
+
+// ScriptProvider supplies a manifest-defined adapter's current PxL script
+// and metric mapping, and signals Changed() whenever either is hot-swapped
+// so the worker loop can pick up the new script without restarting.
+type ScriptProvider interface {
+	Manifest() Manifest
+	Script() string
+	Changed() <-chan struct{}
+	Close() error
+}
+
+// fileScriptProvider loads a manifest and its referenced PxL script from a
+// directory and watches the directory with fsnotify. Kubernetes mounts a
+// ConfigMap as exactly this kind of directory (kubelet atomically repoints
+// a `..data` symlink on update), so watching the directory for create/
+// rename events - rather than the individual files - covers both a plain
+// directory on disk and a projected ConfigMap volume.
+type fileScriptProvider struct {
+	manifestPath string
+
+	mu       sync.RWMutex
+	manifest Manifest
+	script   string
+
+	changed chan struct{}
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// NewFileScriptProvider loads the manifest at manifestPath (and the PxL
+// script it references, resolved relative to the manifest's directory) and
+// starts watching its directory for changes.
+func NewFileScriptProvider(manifestPath string) (ScriptProvider, error) {
+	p := &fileScriptProvider{
+		manifestPath: manifestPath,
+		changed:      make(chan struct{}, 1),
+		done:         make(chan struct{}),
+	}
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("adapter: create fsnotify watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(manifestPath)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("adapter: watch %s: %w", filepath.Dir(manifestPath), err)
+	}
+	p.watcher = watcher
+	go p.watch()
+	return p, nil
+}
+
+func (p *fileScriptProvider) load() error {
+	manifestBytes, err := os.ReadFile(p.manifestPath)
+	if err != nil {
+		return fmt.Errorf("adapter: read manifest %s: %w", p.manifestPath, err)
+	}
+	var m Manifest
+	if err := yaml.Unmarshal(manifestBytes, &m); err != nil {
+		return fmt.Errorf("adapter: parse manifest %s: %w", p.manifestPath, err)
+	}
+
+	scriptPath := m.ScriptPath
+	if !filepath.IsAbs(scriptPath) {
+		scriptPath = filepath.Join(filepath.Dir(p.manifestPath), scriptPath)
+	}
+	scriptBytes, err := os.ReadFile(scriptPath)
+	if err != nil {
+		return fmt.Errorf("adapter: read script %s: %w", scriptPath, err)
+	}
+
+	p.mu.Lock()
+	p.manifest = m
+	p.script = string(scriptBytes)
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *fileScriptProvider) watch() {
+	for {
+		select {
+		case <-p.done:
+			return
+		case event, ok := <-p.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if err := p.load(); err != nil {
+				log.Warnf("adapter: failed to reload manifest %s: %s", p.manifestPath, err)
+				continue
+			}
+			select {
+			case p.changed <- struct{}{}:
+			default:
+			}
+		case err, ok := <-p.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Warnf("adapter: watcher error for %s: %s", p.manifestPath, err)
+		}
+	}
+}
+
+func (p *fileScriptProvider) Manifest() Manifest {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.manifest
+}
+
+func (p *fileScriptProvider) Script() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.script
+}
+
+func (p *fileScriptProvider) Changed() <-chan struct{} {
+	return p.changed
+}
+
+func (p *fileScriptProvider) Close() error {
+	close(p.done)
+	return p.watcher.Close()
+}
@@ -0,0 +1,208 @@
+package adapter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	"px.dev/pxapi/types"
+)
+
+// ResourceHelper builds OTLP resources from Pixie records and tracks the
+// per-series start timestamps that counter metrics need to report correct
+// delta temporality. Only delta is supported; counters are not converted to
+// cumulative temporality.
+//
+// Every record of every script passes through createResources on every
+// collection interval, so in clusters with heavy pod churn (CronJobs, Argo
+// Workflows) the set of distinct resource tuples - and the per-(resource,
+// metric) series derived from them - grows without bound for the lifetime
+// of the process. Both caches are therefore size-bounded LRUs with TTL
+// eviction rather than plain maps, so memory stays flat regardless of
+// churn.
+type ResourceHelper struct {
+	mu               sync.Mutex
+	series           *expirable.LRU[seriesKey, *seriesState]
+	seriesByResource map[string]map[string]struct{} // resourceID -> set of metric names with a live series
+
+	resources *expirable.LRU[string, []*resourcepb.Resource]
+
+	cacheHits    int64
+	cacheMisses  int64
+	cacheEvicted int64
+}
+
+// CacheStats reports the resource cache's hit/miss/eviction counters and
+// current size, for the worker's self-observability metrics.
+type CacheStats struct {
+	Hits    int64
+	Misses  int64
+	Evicted int64
+	Size    int
+}
+
+// seriesKey identifies one counter series. by_k8s-level scripts (like the
+// JVM adapter's) aggregate away the Pixie UPID before Adapt ever sees the
+// record, so the series identity here is the k8s object tuple the script
+// groups by rather than the raw UPID; it plays the same role.
+type seriesKey struct {
+	resourceID string
+	metric     string
+}
+
+type seriesState struct {
+	lastTimestampUnixNano uint64
+}
+
+// NewResourceHelper builds a ResourceHelper whose resource cache holds at
+// most maxEntries tuples, evicting entries that haven't been touched for
+// ttl (an interval's worth of misses means the tuple has likely churned
+// out of the cluster). The series cache is sized generously off the same
+// maxEntries, since a single resource fans out into one series per metric.
+func NewResourceHelper(maxEntries int, ttl time.Duration) *ResourceHelper {
+	rh := &ResourceHelper{seriesByResource: make(map[string]map[string]struct{})}
+	rh.resources = expirable.NewLRU[string, []*resourcepb.Resource](maxEntries, func(key string, _ []*resourcepb.Resource) {
+		atomic.AddInt64(&rh.cacheEvicted, 1)
+		rh.ForgetSeries(k8sResourceIDFromCacheKey(key))
+	}, ttl)
+	rh.series = expirable.NewLRU[seriesKey, *seriesState](maxEntries*8, nil, ttl)
+	return rh
+}
+
+func (rh *ResourceHelper) createResources(r *types.Record, clusterName, pixieClusterID string) []*resourcepb.Resource {
+	key := resourceCacheKey(r, clusterName, pixieClusterID)
+	if resources, ok := rh.resources.Get(key); ok {
+		atomic.AddInt64(&rh.cacheHits, 1)
+		// Get alone doesn't extend the entry's TTL; re-adding does, so only
+		// tuples that stop showing up expire.
+		rh.resources.Add(key, resources)
+		return resources
+	}
+	atomic.AddInt64(&rh.cacheMisses, 1)
+
+	attrs := []*commonpb.KeyValue{
+		stringAttr("cluster.name", clusterName),
+		stringAttr("pixie.cluster.id", pixieClusterID),
+	}
+	for _, col := range []string{"container", "pod", "service", "namespace"} {
+		if v, err := stringDatum(r, col); err == nil {
+			attrs = append(attrs, stringAttr(col, v))
+		}
+	}
+	resources := []*resourcepb.Resource{{Attributes: attrs}}
+	rh.resources.Add(key, resources)
+	return resources
+}
+
+// CacheStats returns the resource cache's hit/miss/eviction counters and
+// current size.
+func (rh *ResourceHelper) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:    atomic.LoadInt64(&rh.cacheHits),
+		Misses:  atomic.LoadInt64(&rh.cacheMisses),
+		Evicted: atomic.LoadInt64(&rh.cacheEvicted),
+		Size:    rh.resources.Len(),
+	}
+}
+
+// resourceCacheKey builds a stable cache key for the (container, pod,
+// service, namespace) tuple a record belongs to, scoped to the cluster so
+// two clusters sharing a pod name don't collide.
+func resourceCacheKey(r *types.Record, clusterName, pixieClusterID string) string {
+	container, _ := stringDatum(r, "container")
+	pod, _ := stringDatum(r, "pod")
+	service, _ := stringDatum(r, "service")
+	namespace, _ := stringDatum(r, "namespace")
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s", clusterName, pixieClusterID, namespace, service, pod, container)
+}
+
+// StartTime returns the OTLP StartTimeUnixNano to report for a delta
+// counter sample identified by resourceID/metricName: the end of the
+// previous window for a series that has been seen before, or the current
+// timestamp if this is the first sample since the series was created or
+// last forgotten (see ForgetSeries).
+//
+// This only ever sees the already-windowed delta PxL computed (e.g.
+// young_gc_time_max - young_gc_time_min), not the raw cumulative JVM
+// counter, so there's no sound way to detect a counter reset from value
+// alone here - that delta is never negative regardless of whether the
+// underlying counter rolled back mid-window. Resets are instead handled by
+// ForgetSeries, which drops a resourceID's bookkeeping once its resource
+// entry ages out of the cache, so a reused identifier doesn't inherit a
+// stale start time.
+func (rh *ResourceHelper) StartTime(resourceID, metricName string, timestampUnixNano uint64) uint64 {
+	key := seriesKey{resourceID: resourceID, metric: metricName}
+	rh.mu.Lock()
+	defer rh.mu.Unlock()
+	start := timestampUnixNano
+	if s, ok := rh.series.Get(key); ok {
+		start = s.lastTimestampUnixNano
+	}
+	rh.series.Add(key, &seriesState{lastTimestampUnixNano: timestampUnixNano})
+
+	metrics, ok := rh.seriesByResource[resourceID]
+	if !ok {
+		metrics = make(map[string]struct{})
+		rh.seriesByResource[resourceID] = metrics
+	}
+	metrics[metricName] = struct{}{}
+	return start
+}
+
+// ForgetSeries drops the start-time bookkeeping for a resource that has
+// disappeared (e.g. its pod no longer shows up in Pixie), so that a reused
+// identifier doesn't inherit a stale start time. It runs automatically
+// whenever that resourceID's entry is evicted from the resource cache (see
+// NewResourceHelper).
+//
+// seriesByResource tracks exactly which metric names have a live series for
+// a resourceID, so this only ever touches that resource's own handful of
+// series - not a scan of the whole (size-bounded, but still large) series
+// cache - which matters because evictions, and so calls to ForgetSeries,
+// cluster during the heavy pod churn createResources is meant to survive.
+func (rh *ResourceHelper) ForgetSeries(resourceID string) {
+	rh.mu.Lock()
+	defer rh.mu.Unlock()
+	metrics, ok := rh.seriesByResource[resourceID]
+	if !ok {
+		return
+	}
+	for metricName := range metrics {
+		rh.series.Remove(seriesKey{resourceID: resourceID, metric: metricName})
+	}
+	delete(rh.seriesByResource, resourceID)
+}
+
+// k8sResourceIDFromCacheKey recovers the k8sResourceID (namespace/service/
+// pod/container) portion of a resourceCacheKey, which additionally prefixes
+// it with clusterName/pixieClusterID, so the resource cache's eviction
+// callback can forget the matching series without the two caches needing a
+// shared key format.
+func k8sResourceIDFromCacheKey(key string) string {
+	parts := strings.Split(key, "/")
+	if len(parts) < 4 {
+		return key
+	}
+	return strings.Join(parts[len(parts)-4:], "/")
+}
+
+func stringDatum(r *types.Record, col string) (string, error) {
+	d := r.GetDatum(col)
+	if d == nil {
+		return "", fmt.Errorf("column %s not present", col)
+	}
+	return fmt.Sprintf("%v", d), nil
+}
+
+func stringAttr(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
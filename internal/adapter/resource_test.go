@@ -0,0 +1,78 @@
+package adapter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResourceHelperStartTime(t *testing.T) {
+	rh := NewResourceHelper(100, time.Hour)
+
+	first := rh.StartTime("ns/svc/pod/container", "young_gc_time", 1000)
+	if first != 1000 {
+		t.Fatalf("first sample: StartTime = %d, want 1000 (its own timestamp)", first)
+	}
+
+	second := rh.StartTime("ns/svc/pod/container", "young_gc_time", 2000)
+	if second != 1000 {
+		t.Fatalf("second sample: StartTime = %d, want 1000 (end of previous window)", second)
+	}
+
+	third := rh.StartTime("ns/svc/pod/container", "young_gc_time", 3000)
+	if third != 2000 {
+		t.Fatalf("third sample: StartTime = %d, want 2000 (end of previous window)", third)
+	}
+}
+
+func TestResourceHelperStartTimeIsPerMetricAndResource(t *testing.T) {
+	rh := NewResourceHelper(100, time.Hour)
+
+	rh.StartTime("ns/svc/pod-a/container", "young_gc_time", 1000)
+	rh.StartTime("ns/svc/pod-a/container", "full_gc_time", 1500)
+
+	// A different metric on the same resource doesn't share state with
+	// young_gc_time's series.
+	if got := rh.StartTime("ns/svc/pod-a/container", "full_gc_time", 2000); got != 1500 {
+		t.Fatalf("full_gc_time StartTime = %d, want 1500", got)
+	}
+
+	// A different resource is an entirely separate series, even for the
+	// same metric name.
+	if got := rh.StartTime("ns/svc/pod-b/container", "young_gc_time", 5000); got != 5000 {
+		t.Fatalf("new resource's first sample StartTime = %d, want 5000 (its own timestamp)", got)
+	}
+}
+
+func TestResourceHelperForgetSeries(t *testing.T) {
+	rh := NewResourceHelper(100, time.Hour)
+
+	rh.StartTime("ns/svc/pod/container", "young_gc_time", 1000)
+	rh.StartTime("ns/svc/pod/container", "full_gc_time", 1200)
+
+	rh.ForgetSeries("ns/svc/pod/container")
+
+	if got := rh.StartTime("ns/svc/pod/container", "young_gc_time", 9000); got != 9000 {
+		t.Errorf("young_gc_time after ForgetSeries: StartTime = %d, want 9000 (new series)", got)
+	}
+	if got := rh.StartTime("ns/svc/pod/container", "full_gc_time", 9500); got != 9500 {
+		t.Errorf("full_gc_time after ForgetSeries: StartTime = %d, want 9500 (new series)", got)
+	}
+}
+
+func TestResourceHelperForgetSeriesLeavesOtherResourcesAlone(t *testing.T) {
+	rh := NewResourceHelper(100, time.Hour)
+
+	rh.StartTime("ns/svc/pod-a/container", "young_gc_time", 1000)
+	rh.StartTime("ns/svc/pod-b/container", "young_gc_time", 2000)
+
+	rh.ForgetSeries("ns/svc/pod-a/container")
+
+	if got := rh.StartTime("ns/svc/pod-b/container", "young_gc_time", 3000); got != 2000 {
+		t.Errorf("unrelated resource's series was disturbed: StartTime = %d, want 2000", got)
+	}
+}
+
+func TestResourceHelperForgetSeriesUnknownResourceIsNoop(t *testing.T) {
+	rh := NewResourceHelper(100, time.Hour)
+	rh.ForgetSeries("never/seen/before/resource")
+}
@@ -0,0 +1,171 @@
+// Package config holds the runtime configuration for the worker pool,
+// sourced from the process environment the same way the rest of the
+// integration is configured.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transport selects the wire protocol used to deliver OTLP payloads to
+// New Relic.
+type Transport string
+
+const (
+	// TransportGRPC sends OTLP payloads over gRPC. This is the default.
+	TransportGRPC Transport = "grpc"
+	// TransportHTTP sends OTLP payloads as HTTP/protobuf.
+	TransportHTTP Transport = "http"
+)
+
+// Worker is the runtime configuration for the Pixie->OTLP worker pool.
+type Worker struct {
+	clusterName    string
+	pixieClusterID string
+
+	otlpEndpoint  string
+	otlpAPIKey    string
+	otlpTransport Transport
+
+	exportQueueMaxSize int
+	exportQueueMaxAge  time.Duration
+	exportMaxRetries   int
+
+	resourceCacheMaxEntries int
+	resourceCacheTTL        time.Duration
+
+	adapterFilters map[string]AdapterFilter
+
+	metricsAddr string
+
+	scriptManifestDir string
+}
+
+// AdapterFilter is the per-adapter metric/attribute filtering config,
+// modeled on the cc-metric-collector pattern where each collector takes a
+// JSON blob with exclude_metrics and unmarshals it in its Init. Glob
+// patterns use path.Match syntax (e.g. "runtime.jvm.memory.*").
+type AdapterFilter struct {
+	IncludeMetrics []string `json:"include_metrics"`
+	ExcludeMetrics []string `json:"exclude_metrics"`
+	AttributeDrop  []string `json:"attribute_drop"`
+}
+
+// NewWorkerFromEnv builds a Worker configuration from the process
+// environment.
+func NewWorkerFromEnv() (Worker, error) {
+	cfg := Worker{
+		clusterName:        os.Getenv("CLUSTER_NAME"),
+		pixieClusterID:     os.Getenv("PIXIE_CLUSTER_ID"),
+		otlpEndpoint:       os.Getenv("NEW_RELIC_OTLP_ENDPOINT"),
+		otlpAPIKey:         os.Getenv("NEW_RELIC_LICENSE_KEY"),
+		otlpTransport:      Transport(strings.ToLower(envDefault("NEW_RELIC_OTLP_TRANSPORT", string(TransportGRPC)))),
+		exportQueueMaxSize: 10000,
+		exportQueueMaxAge:  5 * time.Minute,
+		exportMaxRetries:   5,
+
+		resourceCacheMaxEntries: 50000,
+		resourceCacheTTL:        30 * time.Minute,
+
+		metricsAddr: envDefault("METRICS_ADDR", ":9090"),
+
+		scriptManifestDir: os.Getenv("SCRIPT_MANIFEST_DIR"),
+	}
+	if cfg.clusterName == "" {
+		return Worker{}, fmt.Errorf("config: CLUSTER_NAME must be set")
+	}
+	if cfg.otlpEndpoint == "" {
+		return Worker{}, fmt.Errorf("config: NEW_RELIC_OTLP_ENDPOINT must be set")
+	}
+
+	var err error
+	if cfg.exportQueueMaxSize, err = intEnvDefault("EXPORT_QUEUE_MAX_SIZE", cfg.exportQueueMaxSize); err != nil {
+		return Worker{}, err
+	}
+	if cfg.exportMaxRetries, err = intEnvDefault("EXPORT_MAX_RETRIES", cfg.exportMaxRetries); err != nil {
+		return Worker{}, err
+	}
+	if v := os.Getenv("EXPORT_QUEUE_MAX_AGE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return Worker{}, fmt.Errorf("config: invalid EXPORT_QUEUE_MAX_AGE: %w", err)
+		}
+		cfg.exportQueueMaxAge = d
+	}
+	if cfg.resourceCacheMaxEntries, err = intEnvDefault("RESOURCE_CACHE_MAX_ENTRIES", cfg.resourceCacheMaxEntries); err != nil {
+		return Worker{}, err
+	}
+	if v := os.Getenv("RESOURCE_CACHE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return Worker{}, fmt.Errorf("config: invalid RESOURCE_CACHE_TTL: %w", err)
+		}
+		cfg.resourceCacheTTL = d
+	}
+	if v := os.Getenv("ADAPTER_FILTERS"); v != "" {
+		if err := json.Unmarshal([]byte(v), &cfg.adapterFilters); err != nil {
+			return Worker{}, fmt.Errorf("config: invalid ADAPTER_FILTERS: %w", err)
+		}
+	}
+
+	switch cfg.otlpTransport {
+	case TransportGRPC, TransportHTTP:
+	default:
+		return Worker{}, fmt.Errorf("config: unsupported NEW_RELIC_OTLP_TRANSPORT %q", cfg.otlpTransport)
+	}
+
+	return cfg, nil
+}
+
+func envDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func intEnvDefault(key string, def int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("config: invalid %s: %w", key, err)
+	}
+	return n, nil
+}
+
+func (c Worker) ClusterName() string    { return c.clusterName }
+func (c Worker) PixieClusterID() string { return c.pixieClusterID }
+
+func (c Worker) OTLPEndpoint() string     { return c.otlpEndpoint }
+func (c Worker) OTLPAPIKey() string       { return c.otlpAPIKey }
+func (c Worker) OTLPTransport() Transport { return c.otlpTransport }
+
+func (c Worker) ExportQueueMaxSize() int          { return c.exportQueueMaxSize }
+func (c Worker) ExportQueueMaxAge() time.Duration { return c.exportQueueMaxAge }
+func (c Worker) ExportMaxRetries() int            { return c.exportMaxRetries }
+
+func (c Worker) ResourceCacheMaxEntries() int    { return c.resourceCacheMaxEntries }
+func (c Worker) ResourceCacheTTL() time.Duration { return c.resourceCacheTTL }
+
+// AdapterFilter returns the filter configuration for the adapter with the
+// given ID, or the zero value (no filtering) if none was configured.
+func (c Worker) AdapterFilter(id string) AdapterFilter {
+	return c.adapterFilters[id]
+}
+
+// MetricsAddr is the address the self-observability /metrics endpoint
+// listens on.
+func (c Worker) MetricsAddr() string { return c.metricsAddr }
+
+// ScriptManifestDir, when set, is a directory of ScriptProvider manifests
+// (see internal/adapter.Manifest) to load in addition to the built-in
+// adapters. Empty means no dynamically-loaded scripts are configured.
+func (c Worker) ScriptManifestDir() string { return c.scriptManifestDir }
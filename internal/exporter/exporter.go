@@ -0,0 +1,40 @@
+// Package exporter delivers the OTLP resource metrics/spans produced by the
+// adapters to New Relic over HTTP or gRPC, with a bounded queue and retry
+// logic so a slow or unavailable endpoint cannot stall Pixie script
+// collection.
+package exporter
+
+import (
+	"context"
+
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"github.com/newrelic/newrelic-pixie-integration/internal/config"
+)
+
+// Exporter accepts OTLP resource metrics/spans and delivers them to New
+// Relic. Export calls never block on the network: they enqueue onto a
+// bounded, retrying queue and return immediately.
+type Exporter interface {
+	ExportMetrics(rm []*metricpb.ResourceMetrics)
+	ExportSpans(rs []*tracepb.ResourceSpans)
+
+	// Stats reports the current queue depth and the per-signal retry/drop
+	// counters accumulated since the exporter was created.
+	Stats() Stats
+
+	// Shutdown drains whatever is still queued, bounded by ctx, then stops
+	// accepting new data. Safe to call more than once and from multiple
+	// goroutines.
+	Shutdown(ctx context.Context)
+}
+
+// New builds the configured Exporter and starts its background retry loop.
+func New(cfg config.Worker) (Exporter, error) {
+	t, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newQueuedExporter(cfg, t), nil
+}
@@ -0,0 +1,223 @@
+package exporter
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/newrelic/infrastructure-agent/pkg/log"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"github.com/newrelic/newrelic-pixie-integration/internal/config"
+)
+
+// Stats exposes the counters operators need to notice data loss in the
+// export pipeline.
+type Stats struct {
+	QueueDepth     int64
+	MetricsRetries int64
+	SpansRetries   int64
+	MetricsDropped int64
+	SpansDropped   int64
+}
+
+// batch is a single queued unit of work: either a metrics or a spans
+// payload, tagged with the time it was enqueued so the max-age cutoff can
+// apply and the number of attempts already made against it.
+type batch struct {
+	enqueuedAt time.Time
+	attempts   int
+	metrics    []*metricpb.ResourceMetrics
+	spans      []*tracepb.ResourceSpans
+}
+
+// queuedExporter is the default Exporter: a bounded channel per process
+// drained by a single goroutine that retries retryable failures with
+// exponential backoff and jitter, dropping batches that exceed the
+// configured retry count or max age.
+type queuedExporter struct {
+	transport transport
+
+	queue      chan *batch
+	maxRetries int
+	maxAge     time.Duration
+
+	metricsRetries int64
+	spansRetries   int64
+	metricsDropped int64
+	spansDropped   int64
+
+	shutdownOnce sync.Once
+	done         chan struct{}
+	wg           sync.WaitGroup
+}
+
+func newQueuedExporter(cfg config.Worker, t transport) *queuedExporter {
+	e := &queuedExporter{
+		transport:  t,
+		queue:      make(chan *batch, cfg.ExportQueueMaxSize()),
+		maxRetries: cfg.ExportMaxRetries(),
+		maxAge:     cfg.ExportQueueMaxAge(),
+		done:       make(chan struct{}),
+	}
+	e.wg.Add(1)
+	go e.drain()
+	return e
+}
+
+func (e *queuedExporter) ExportMetrics(rm []*metricpb.ResourceMetrics) {
+	e.enqueue(&batch{enqueuedAt: time.Now(), metrics: rm})
+}
+
+func (e *queuedExporter) ExportSpans(rs []*tracepb.ResourceSpans) {
+	e.enqueue(&batch{enqueuedAt: time.Now(), spans: rs})
+}
+
+func (e *queuedExporter) Stats() Stats {
+	return Stats{
+		QueueDepth:     int64(len(e.queue)),
+		MetricsRetries: atomic.LoadInt64(&e.metricsRetries),
+		SpansRetries:   atomic.LoadInt64(&e.spansRetries),
+		MetricsDropped: atomic.LoadInt64(&e.metricsDropped),
+		SpansDropped:   atomic.LoadInt64(&e.spansDropped),
+	}
+}
+
+func (e *queuedExporter) enqueue(b *batch) {
+	select {
+	case <-e.done:
+		e.drop(b, "exporter is shut down")
+		return
+	default:
+	}
+	select {
+	case e.queue <- b:
+	default:
+		e.drop(b, "export queue is full")
+	}
+}
+
+func (e *queuedExporter) drop(b *batch, reason string) {
+	if b.metrics != nil {
+		atomic.AddInt64(&e.metricsDropped, 1)
+		log.Warnf("exporter: dropping metrics batch: %s", reason)
+	}
+	if b.spans != nil {
+		atomic.AddInt64(&e.spansDropped, 1)
+		log.Warnf("exporter: dropping spans batch: %s", reason)
+	}
+}
+
+func (e *queuedExporter) drain() {
+	defer e.wg.Done()
+	for {
+		select {
+		case b := <-e.queue:
+			e.send(b, true)
+		case <-e.done:
+			e.flush()
+			return
+		}
+	}
+}
+
+// flush sends whatever is left on the queue at shutdown, best effort, with
+// no further retries of its own - any retry already in flight from the
+// normal drain() path is tracked separately via e.wg (see send) and either
+// fires before flush runs or is dropped instead of re-enqueued once it
+// sees e.done closed.
+func (e *queuedExporter) flush() {
+	for {
+		select {
+		case b := <-e.queue:
+			e.send(b, false)
+		default:
+			return
+		}
+	}
+}
+
+// send delivers b, dropping it on a non-retryable error, exhausted retries,
+// or (when allowRetry is false, i.e. during shutdown's flush) any retryable
+// error too. allowRetry schedules a retry via time.AfterFunc instead of
+// dropping.
+func (e *queuedExporter) send(b *batch, allowRetry bool) {
+	if e.maxAge > 0 && time.Since(b.enqueuedAt) > e.maxAge {
+		e.drop(b, "exceeded max queue age")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var retryable bool
+	var err error
+	if b.metrics != nil {
+		retryable, err = e.transport.sendMetrics(ctx, b.metrics)
+	} else {
+		retryable, err = e.transport.sendSpans(ctx, b.spans)
+	}
+	if err == nil {
+		return
+	}
+	if !allowRetry || !retryable || b.attempts >= e.maxRetries {
+		e.drop(b, err.Error())
+		return
+	}
+
+	b.attempts++
+	if b.metrics != nil {
+		atomic.AddInt64(&e.metricsRetries, 1)
+	} else {
+		atomic.AddInt64(&e.spansRetries, 1)
+	}
+	delay := backoff(b.attempts)
+	log.Warnf("exporter: retrying batch in %s after error: %s", delay, err)
+
+	// e.wg also covers this timer, not just drain(), so Shutdown's wg.Wait()
+	// can't return while a retry is still in flight and land the batch back
+	// in e.queue after nothing is left to read it.
+	e.wg.Add(1)
+	time.AfterFunc(delay, func() {
+		defer e.wg.Done()
+		select {
+		case <-e.done:
+			e.drop(b, "exporter shutting down before retry")
+		default:
+			e.enqueue(b)
+		}
+	})
+}
+
+func (e *queuedExporter) Shutdown(ctx context.Context) {
+	e.shutdownOnce.Do(func() {
+		close(e.done)
+		stopped := make(chan struct{})
+		go func() {
+			e.wg.Wait()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			log.Warnf("exporter: shutdown deadline hit with data still queued")
+		}
+		if err := e.transport.close(); err != nil {
+			log.Warnf("exporter: error closing transport: %s", err)
+		}
+	})
+}
+
+// backoff returns an exponential delay with full jitter for the given retry
+// attempt (1-indexed), capped at 30s.
+func backoff(attempt int) time.Duration {
+	max := 30 * time.Second
+	base := time.Duration(1<<uint(attempt-1)) * time.Second
+	if base > max || base <= 0 {
+		base = max
+	}
+	return time.Duration(rand.Int63n(int64(base) + 1))
+}
@@ -0,0 +1,35 @@
+package exporter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	max := 30 * time.Second
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay := backoff(attempt)
+		if delay < 0 || delay > max {
+			t.Errorf("backoff(%d) = %s, want in [0, %s]", attempt, delay, max)
+		}
+	}
+}
+
+func TestBackoffCapsAtMax(t *testing.T) {
+	// Full jitter means any single sample can land anywhere in [0, base], so
+	// assert on the upper bound implied by each attempt's base delay instead
+	// of comparing individual samples.
+	caps := map[int]time.Duration{
+		1: 1 * time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		6: 30 * time.Second, // 2^5s = 32s > cap, so base clamps to 30s
+	}
+	for attempt, cap := range caps {
+		for i := 0; i < 20; i++ {
+			if delay := backoff(attempt); delay > cap {
+				t.Fatalf("backoff(%d) = %s, want <= %s", attempt, delay, cap)
+			}
+		}
+	}
+}
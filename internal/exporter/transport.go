@@ -0,0 +1,156 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"github.com/newrelic/newrelic-pixie-integration/internal/config"
+)
+
+// transport delivers a single OTLP batch and classifies a failure as
+// retryable (rate limiting, server errors, transient network errors) or
+// terminal (bad payload, auth failure) so the queue knows whether to retry.
+type transport interface {
+	sendMetrics(ctx context.Context, rm []*metricpb.ResourceMetrics) (retryable bool, err error)
+	sendSpans(ctx context.Context, rs []*tracepb.ResourceSpans) (retryable bool, err error)
+	close() error
+}
+
+func newTransport(cfg config.Worker) (transport, error) {
+	switch cfg.OTLPTransport() {
+	case config.TransportGRPC:
+		return newGRPCTransport(cfg)
+	case config.TransportHTTP:
+		return newHTTPTransport(cfg), nil
+	default:
+		return nil, fmt.Errorf("exporter: unsupported transport %q", cfg.OTLPTransport())
+	}
+}
+
+// httpTransport ships OTLP/HTTP protobuf requests directly to the New Relic
+// OTLP endpoint.
+type httpTransport struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+}
+
+func newHTTPTransport(cfg config.Worker) *httpTransport {
+	return &httpTransport{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		endpoint: cfg.OTLPEndpoint(),
+		apiKey:   cfg.OTLPAPIKey(),
+	}
+}
+
+func (t *httpTransport) sendMetrics(ctx context.Context, rm []*metricpb.ResourceMetrics) (bool, error) {
+	req := &colmetricpb.ExportMetricsServiceRequest{ResourceMetrics: rm}
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return false, fmt.Errorf("exporter: marshal metrics request: %w", err)
+	}
+	return t.post(ctx, "/v1/metrics", body)
+}
+
+func (t *httpTransport) sendSpans(ctx context.Context, rs []*tracepb.ResourceSpans) (bool, error) {
+	req := &coltracepb.ExportTraceServiceRequest{ResourceSpans: rs}
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return false, fmt.Errorf("exporter: marshal spans request: %w", err)
+	}
+	return t.post(ctx, "/v1/traces", body)
+}
+
+func (t *httpTransport) post(ctx context.Context, path string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("exporter: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Api-Key", t.apiKey)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// Network errors (timeouts, connection refused, DNS) are transient.
+		return true, fmt.Errorf("exporter: http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusAccepted {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("exporter: http status %d", resp.StatusCode)
+}
+
+func (t *httpTransport) close() error { return nil }
+
+// grpcTransport ships OTLP/gRPC requests to the New Relic OTLP endpoint.
+type grpcTransport struct {
+	conn          *grpc.ClientConn
+	metricsClient colmetricpb.MetricsServiceClient
+	tracesClient  coltracepb.TraceServiceClient
+	apiKey        string
+}
+
+func newGRPCTransport(cfg config.Worker) (*grpcTransport, error) {
+	conn, err := grpc.Dial(cfg.OTLPEndpoint(), grpc.WithTransportCredentials(credentials.NewTLS(nil)))
+	if err != nil {
+		return nil, fmt.Errorf("exporter: dial gRPC endpoint: %w", err)
+	}
+	return &grpcTransport{
+		conn:          conn,
+		metricsClient: colmetricpb.NewMetricsServiceClient(conn),
+		tracesClient:  coltracepb.NewTraceServiceClient(conn),
+		apiKey:        cfg.OTLPAPIKey(),
+	}, nil
+}
+
+func (t *grpcTransport) sendMetrics(ctx context.Context, rm []*metricpb.ResourceMetrics) (bool, error) {
+	_, err := t.metricsClient.Export(t.withAPIKey(ctx), &colmetricpb.ExportMetricsServiceRequest{ResourceMetrics: rm})
+	return classifyGRPCError(err)
+}
+
+func (t *grpcTransport) sendSpans(ctx context.Context, rs []*tracepb.ResourceSpans) (bool, error) {
+	_, err := t.tracesClient.Export(t.withAPIKey(ctx), &coltracepb.ExportTraceServiceRequest{ResourceSpans: rs})
+	return classifyGRPCError(err)
+}
+
+func (t *grpcTransport) withAPIKey(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "api-key", t.apiKey)
+}
+
+func (t *grpcTransport) close() error { return t.conn.Close() }
+
+// classifyGRPCError reports whether a gRPC export error is worth retrying:
+// rate limiting, unavailability, deadline exceeded, aborted, and internal
+// server errors are transient, everything else (bad payload, auth failure)
+// is not.
+func classifyGRPCError(err error) (bool, error) {
+	if err == nil {
+		return false, nil
+	}
+	switch status.Code(err) {
+	case codes.ResourceExhausted, codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Aborted:
+		return true, err
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,38 @@
+package exporter
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifyGRPCError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantRetryable bool
+	}{
+		{"nil error", nil, false},
+		{"resource exhausted", status.Error(codes.ResourceExhausted, "rate limited"), true},
+		{"unavailable", status.Error(codes.Unavailable, "down"), true},
+		{"deadline exceeded", status.Error(codes.DeadlineExceeded, "timeout"), true},
+		{"internal", status.Error(codes.Internal, "oops"), true},
+		{"aborted", status.Error(codes.Aborted, "conflict"), true},
+		{"invalid argument", status.Error(codes.InvalidArgument, "bad payload"), false},
+		{"unauthenticated", status.Error(codes.Unauthenticated, "bad api key"), false},
+		{"non-status error", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryable, err := classifyGRPCError(tt.err)
+			if retryable != tt.wantRetryable {
+				t.Errorf("classifyGRPCError(%v) retryable = %v, want %v", tt.err, retryable, tt.wantRetryable)
+			}
+			if tt.err == nil && err != nil {
+				t.Errorf("classifyGRPCError(nil) err = %v, want nil", err)
+			}
+		})
+	}
+}
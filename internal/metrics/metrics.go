@@ -0,0 +1,132 @@
+// Package metrics exposes a Prometheus /metrics endpoint for the
+// Pixie->OTLP pipeline's own health: per-script execution time and
+// throughput, Pixie errors by type, overrun detection, and exporter queue
+// health. The worker loop only logged these before, leaving operators
+// without anything machine-readable to alert on.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/newrelic/newrelic-pixie-integration/internal/adapter"
+	"github.com/newrelic/newrelic-pixie-integration/internal/exporter"
+)
+
+const namespace = "pixie_integration"
+
+var (
+	ScriptDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "script_duration_seconds",
+		Help:      "Duration of each Pixie script execution.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"script"})
+
+	RecordsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "script_records_sent_total",
+		Help:      "Records sent to the exporter, by script.",
+	}, []string{"script"})
+
+	ScriptErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "script_errors_total",
+		Help:      "Pixie script execution errors, by script and error type.",
+	}, []string{"script", "type"})
+
+	ScriptOverrunTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "script_overrun_total",
+		Help:      "Collection intervals a script's execution overran, skipping the sleep.",
+	}, []string{"script"})
+
+	ExporterQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exporter_queue_depth",
+		Help:      "Current depth of the exporter's retry queue.",
+	})
+
+	ExporterRetries = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exporter_retries_total",
+		Help:      "Retry attempts made by the exporter, by signal.",
+	}, []string{"signal"})
+
+	ExporterDropped = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exporter_dropped_total",
+		Help:      "Batches dropped by the exporter, by signal.",
+	}, []string{"signal"})
+
+	ResourceCache = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "resource_cache",
+		Help:      "ResourceHelper's resource cache hits/misses/evictions/size.",
+	}, []string{"stat"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		ScriptDuration, RecordsSent, ScriptErrors, ScriptOverrunTotal,
+		ExporterQueueDepth, ExporterRetries, ExporterDropped, ResourceCache,
+	)
+}
+
+// Handler returns the HTTP handler that serves the registered metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Serve starts the /metrics HTTP server and returns it so the caller can
+// shut it down on ctx cancellation.
+func Serve(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go srv.ListenAndServe()
+	return srv
+}
+
+// WatchExporter periodically copies the exporter's queue depth and
+// retry/drop counters into the Prometheus gauges above, until ctx is
+// cancelled.
+func WatchExporter(ctx context.Context, e exporter.Exporter, interval time.Duration) {
+	watch(ctx, interval, func() {
+		stats := e.Stats()
+		ExporterQueueDepth.Set(float64(stats.QueueDepth))
+		ExporterRetries.WithLabelValues("metrics").Set(float64(stats.MetricsRetries))
+		ExporterRetries.WithLabelValues("spans").Set(float64(stats.SpansRetries))
+		ExporterDropped.WithLabelValues("metrics").Set(float64(stats.MetricsDropped))
+		ExporterDropped.WithLabelValues("spans").Set(float64(stats.SpansDropped))
+	})
+}
+
+// WatchResourceCache periodically copies the resource cache's counters into
+// the Prometheus gauge above, until ctx is cancelled.
+func WatchResourceCache(ctx context.Context, rh *adapter.ResourceHelper, interval time.Duration) {
+	watch(ctx, interval, func() {
+		stats := rh.CacheStats()
+		ResourceCache.WithLabelValues("hits").Set(float64(stats.Hits))
+		ResourceCache.WithLabelValues("misses").Set(float64(stats.Misses))
+		ResourceCache.WithLabelValues("evicted").Set(float64(stats.Evicted))
+		ResourceCache.WithLabelValues("size").Set(float64(stats.Size))
+	})
+}
+
+func watch(ctx context.Context, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fn()
+		}
+	}
+}
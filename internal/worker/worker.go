@@ -11,6 +11,7 @@ import (
 	"github.com/newrelic/newrelic-pixie-integration/internal/adapter"
 	"github.com/newrelic/newrelic-pixie-integration/internal/config"
 	"github.com/newrelic/newrelic-pixie-integration/internal/exporter"
+	"github.com/newrelic/newrelic-pixie-integration/internal/metrics"
 	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
 	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
 	"px.dev/pxapi"
@@ -21,6 +22,24 @@ type Worker interface {
 	Metrics(adapter.MetricsAdapter, *sync.WaitGroup)
 }
 
+// scriptSource is the subset of MetricsAdapter/SpansAdapter that run needs;
+// it re-reads Script()/CollectIntervalSec() at the top of every iteration so
+// a ScriptProvider-backed adapter's hot-swapped script takes effect on the
+// next collection without restarting the worker goroutine.
+type scriptSource interface {
+	ID() string
+	CollectIntervalSec() int64
+	Script() string
+}
+
+// hotReloadable is implemented by adapters whose script can change between
+// collection intervals (see adapter.ScriptProvider). run selects on
+// Changed() so an in-flight ExecuteScript is cancelled as soon as a new
+// script is ready instead of running out the rest of the interval.
+type hotReloadable interface {
+	Changed() <-chan struct{}
+}
+
 type worker struct {
 	ctx         context.Context
 	clusterName string
@@ -39,39 +58,54 @@ func Build(ctx context.Context, cfg config.Worker, vz *pxapi.VizierClient, expor
 	}
 }
 
-func (w *worker) Metrics(adapter adapter.MetricsAdapter, wg *sync.WaitGroup) {
+func (w *worker) Metrics(a adapter.MetricsAdapter, wg *sync.WaitGroup) {
 	h := &metricsHandler{
 		handler: &handler{},
-		adapter: adapter,
+		adapter: a,
 		resourceHelper: w.resourceHelper,
 		metrics: make([]*metricpb.ResourceMetrics, 0),
 	}
-	w.run(w.ctx, wg, adapter.ID(), adapter.Script(), adapter.CollectIntervalSec(), h)
+	w.run(w.ctx, wg, a, h)
 }
 
-func (w *worker) Spans(adapter adapter.SpansAdapter, wg *sync.WaitGroup) {
+func (w *worker) Spans(a adapter.SpansAdapter, wg *sync.WaitGroup) {
 	h := &spansHandler{
 		handler: &handler{},
-		adapter: adapter,
+		adapter: a,
 		resourceHelper: w.resourceHelper,
 		spans:   make([]*tracepb.ResourceSpans, 0),
 	}
-	w.run(w.ctx, wg, adapter.ID(), adapter.Script(), adapter.CollectIntervalSec(), h)
+	w.run(w.ctx, wg, a, h)
 }
 
-func (w *worker) run(ctx context.Context, wg *sync.WaitGroup, name string, script string, collectIntervalSec int64, h customHandler) {
+func (w *worker) run(ctx context.Context, wg *sync.WaitGroup, a scriptSource, h customHandler) {
+	name := a.ID()
 	defer func() {
 		if err := recover(); err != nil {
 			log.Warn(err)
 			log.Infof("sleep 10 seconds to be recovered")
 			time.Sleep(10 * time.Second)
-			w.run(ctx, wg, name, script, collectIntervalSec, h)
+			w.run(ctx, wg, a, h)
 		}
 	}()
 	rm := &ResultMuxer{h}
-	collectInterval := time.Duration(collectIntervalSec) * time.Second
-	maxExecutionTime := time.Duration(collectIntervalSec - 1) * time.Second
+
+	var changed <-chan struct{}
+	if hr, ok := a.(hotReloadable); ok {
+		changed = hr.Changed()
+	}
+
 	for {
+		// A change signalled while sleeping is picked up by the Script() read
+		// below, so it must not also cancel the execution that runs it.
+		select {
+		case <-changed:
+		default:
+		}
+		script := a.Script()
+		collectInterval := time.Duration(a.CollectIntervalSec()) * time.Second
+		maxExecutionTime := time.Duration(a.CollectIntervalSec()-1) * time.Second
+
 		var resultSet *pxapi.ScriptResults
 		select {
 		case <-ctx.Done():
@@ -86,15 +120,18 @@ func (w *worker) run(ctx context.Context, wg *sync.WaitGroup, name string, scrip
 				log.Debugf("executing Pixie script %s\n", name)
 				resultSet, err := w.vz.ExecuteScript(pixieCtx, script, rm)
 				if err != nil && err != io.EOF {
+					metrics.ScriptErrors.WithLabelValues(name, "execution").Inc()
 					ch <- fmt.Errorf("error while executing Pixie script: %s", err)
 					return
 				}
 				log.Debugf("streaming results for %s\n", name)
 				if err := resultSet.Stream(); err != nil {
+					metrics.ScriptErrors.WithLabelValues(name, "streaming").Inc()
 					ch <- fmt.Errorf("pixie streaming error: %s", err)
 					return
 				}
 				records := h.send(w.exporter)
+				metrics.RecordsSent.WithLabelValues(name).Add(float64(records))
 				log.Debugf("done streaming %d results for %s\n", records, name)
 				ch <- nil
 			}()
@@ -107,8 +144,13 @@ func (w *worker) run(ctx context.Context, wg *sync.WaitGroup, name string, scrip
 				}
 			case <-time.After(maxExecutionTime):
 				cancelFn()
+				metrics.ScriptErrors.WithLabelValues(name, "timeout").Inc()
 				log.Warnf("execution out of time for %s!", name)
+			case <-changed:
+				cancelFn()
+				log.Infof("script changed for %s, restarting execution", name)
 			}
+			metrics.ScriptDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
 			if resultSet != nil {
 				resultSet.Close()
 			}
@@ -116,6 +158,7 @@ func (w *worker) run(ctx context.Context, wg *sync.WaitGroup, name string, scrip
 			if (sleepTime > 0) {
 				time.Sleep(sleepTime)
 			} else {
+				metrics.ScriptOverrunTotal.WithLabelValues(name).Inc()
 				log.Warnf("skipping the sleep for %s!", name)
 			}
 		}